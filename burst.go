@@ -0,0 +1,135 @@
+package rlimit
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// BurstLimiter is a token-bucket rate limiter: it allows up to B tokens to
+// be consumed back-to-back (a burst), and refills the entire bucket in one
+// shot at the end of each interval, rather than trickling tokens in one at
+// a time like RateLimiter does. It's a better fit for callers that are fine
+// waiting once they've used up a burst, rather than being spread out evenly.
+type BurstLimiter struct {
+	mu sync.Mutex
+
+	// Maximum number of tokens the bucket can hold
+	capacity uint
+
+	// Tokens currently available
+	tokens uint
+
+	// How often the bucket is refilled to capacity
+	interval time.Duration
+
+	// Timer that fires when the bucket should be refilled
+	timer *time.Timer
+
+	// Channel to carry the stop event - closing it broadcasts to every
+	// blocked WaitN() caller
+	stop chan bool
+
+	// Closed and replaced on every refill to broadcast to any WaitN()
+	// callers blocked waiting for tokens to become available
+	refilled chan struct{}
+}
+
+// NewBurstLimiter creates a new token-bucket limiter with the given burst
+// capacity, refilled in full every interval.
+func NewBurstLimiter(capacity uint, interval time.Duration) *BurstLimiter {
+	b := &BurstLimiter{
+		capacity: capacity,
+		tokens:   capacity,
+		interval: interval,
+		timer:    time.NewTimer(interval),
+		stop:     make(chan bool),
+		refilled: make(chan struct{}),
+	}
+
+	go func() {
+		for {
+			select {
+			case <-b.timer.C:
+				b.mu.Lock()
+				b.tokens = b.capacity
+				close(b.refilled)
+				b.refilled = make(chan struct{})
+				b.mu.Unlock()
+				b.timer.Reset(b.interval)
+			case <-b.stop:
+				return
+			}
+		}
+	}()
+
+	return b
+}
+
+// Stops the internal refill timer.
+func (b *BurstLimiter) Stop() {
+	b.timer.Stop()
+	close(b.stop)
+}
+
+// Blocks until a single token is available. Returns the time the token was
+// reserved, and an error if the limiter has been stopped.
+func (b *BurstLimiter) Wait() (time.Time, error) {
+	return b.WaitN(1)
+}
+
+// WaitN blocks until n tokens are available, reserving all of them at once.
+// Useful for weighted operations that should count as more than a single
+// unit against the burst capacity. Returns an error immediately if n
+// exceeds the bucket's capacity, since it could otherwise never be
+// satisfied.
+func (b *BurstLimiter) WaitN(n uint) (time.Time, error) {
+	if n > b.capacity {
+		return time.Time{}, errors.New("requested tokens exceed burst capacity")
+	}
+
+	for {
+		b.mu.Lock()
+
+		select {
+		case <-b.stop:
+			b.mu.Unlock()
+			return time.Time{}, errors.New("Rate limiter has stopped")
+		default:
+		}
+
+		if b.tokens >= n {
+			b.tokens -= n
+			now := time.Now()
+			b.mu.Unlock()
+			return now, nil
+		}
+
+		waitC := b.refilled
+		b.mu.Unlock()
+
+		select {
+		case <-waitC:
+			// Bucket was refilled - loop around and try again
+		case <-b.stop:
+			return time.Time{}, errors.New("Rate limiter has stopped")
+		}
+	}
+}
+
+// Decrements the token count by one - not to be used when waiting for a
+// token using Wait()/WaitN().
+func (b *BurstLimiter) Count() {
+	b.mu.Lock()
+	if b.tokens > 0 {
+		b.tokens--
+	}
+	b.mu.Unlock()
+}
+
+// Returns the number of tokens currently available in the bucket.
+func (b *BurstLimiter) LimitLeft() uint {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.tokens
+}
@@ -0,0 +1,42 @@
+package rlimit
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestBurstLimiter(t *testing.T) {
+	b := NewBurstLimiter(3, time.Duration(100)*time.Millisecond)
+	defer b.Stop()
+
+	assert.EqualValues(t, 3, b.LimitLeft())
+
+	// The whole burst should be available back-to-back
+	for i := 0; i < 3; i++ {
+		_, err := b.Wait()
+		assert.NoError(t, err)
+	}
+	assert.EqualValues(t, 0, b.LimitLeft())
+
+	// Wait for the bucket to refill
+	_, err := b.Wait()
+	assert.NoError(t, err)
+}
+
+func TestBurstLimiterWaitN(t *testing.T) {
+	b := NewBurstLimiter(5, time.Second)
+	defer b.Stop()
+
+	_, err := b.WaitN(3)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, b.LimitLeft())
+}
+
+func TestBurstLimiterWaitNExceedsCapacity(t *testing.T) {
+	b := NewBurstLimiter(3, time.Second)
+	defer b.Stop()
+
+	_, err := b.WaitN(10)
+	assert.Error(t, err)
+}
@@ -0,0 +1,29 @@
+package rlimit
+
+import "time"
+
+// Limiter is the common surface implemented by both RateLimiter and
+// BurstLimiter, letting downstream code swap between "spread out evenly"
+// (RateLimiter) and "burst then wait" (BurstLimiter) behavior without
+// caring which one it holds.
+type Limiter interface {
+	// Wait blocks until a tick/token is available. Returns the time it
+	// became available, and an error if the limiter has been stopped.
+	Wait() (time.Time, error)
+
+	// Count decrements the limit left - not to be used when waiting
+	// for a tick/token using Wait().
+	Count()
+
+	// LimitLeft returns the number of ticks/tokens left before the
+	// limiter blocks.
+	LimitLeft() uint
+
+	// Stop stops the limiter's internal timers.
+	Stop()
+}
+
+var (
+	_ Limiter = (*RateLimiter)(nil)
+	_ Limiter = (*BurstLimiter)(nil)
+)
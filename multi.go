@@ -0,0 +1,116 @@
+package rlimit
+
+import (
+	"sync"
+	"time"
+)
+
+// MultiRateLimiter manages a set of independent RateLimiters keyed by an
+// arbitrary string, such as a host, API token, or user ID. It's useful for
+// processes that talk to many upstream APIs, each with its own quota, from
+// a single place without having to juggle a RateLimiter per caller by hand.
+type MultiRateLimiter struct {
+	mu sync.RWMutex
+
+	limiters map[string]*RateLimiter
+
+	// Used to lazily create a limiter for a key that hasn't been
+	// registered with Add() when Wait() is called.
+	defaultTickInterval  time.Duration
+	defaultLimit         uint
+	defaultResetInterval time.Duration
+}
+
+// NewMultiRateLimiter creates a MultiRateLimiter. The tickInterval, limit,
+// and resetInterval arguments configure the default RateLimiter that will
+// be lazily created for any key passed to Wait() before it has been
+// registered with Add().
+func NewMultiRateLimiter(tickInterval time.Duration, limit uint, resetInterval time.Duration) *MultiRateLimiter {
+	return &MultiRateLimiter{
+		limiters:             make(map[string]*RateLimiter),
+		defaultTickInterval:  tickInterval,
+		defaultLimit:         limit,
+		defaultResetInterval: resetInterval,
+	}
+}
+
+// Add registers a new RateLimiter for key, configured with the given
+// tickInterval, limit, and resetInterval. If a limiter already exists for
+// key, it is stopped and replaced.
+func (m *MultiRateLimiter) Add(key string, tickInterval time.Duration, limit uint, resetInterval time.Duration) {
+	r := NewRateLimiter(tickInterval, limit, resetInterval)
+
+	m.mu.Lock()
+	if existing, ok := m.limiters[key]; ok {
+		existing.Stop()
+	}
+	m.limiters[key] = r
+	m.mu.Unlock()
+}
+
+// get returns the limiter for key, lazily creating one using the default
+// configuration if it doesn't exist yet.
+func (m *MultiRateLimiter) get(key string) *RateLimiter {
+	m.mu.RLock()
+	r, ok := m.limiters[key]
+	m.mu.RUnlock()
+
+	if ok {
+		return r
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// Check again in case another goroutine created it while we
+	// waited for the write lock.
+	if r, ok := m.limiters[key]; ok {
+		return r
+	}
+
+	r = NewRateLimiter(m.defaultTickInterval, m.defaultLimit, m.defaultResetInterval)
+	m.limiters[key] = r
+	return r
+}
+
+// Wait blocks until the next tick for the limiter associated with key,
+// lazily creating a limiter using the default configuration if key hasn't
+// been registered with Add(). Returns the time of the tick, and an error
+// if that limiter has been stopped.
+func (m *MultiRateLimiter) Wait(key string) (time.Time, error) {
+	return m.get(key).Wait()
+}
+
+// Count decrements the limit left for key - not to be used when waiting
+// for a tick using Wait().
+func (m *MultiRateLimiter) Count(key string) {
+	m.get(key).Count()
+}
+
+// LimitLeft returns the number of ticks left for key until its limiter
+// blocks and waits for the reset.
+func (m *MultiRateLimiter) LimitLeft(key string) uint {
+	return m.get(key).LimitLeft()
+}
+
+// Remove stops and removes the limiter associated with key, if one exists.
+func (m *MultiRateLimiter) Remove(key string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if r, ok := m.limiters[key]; ok {
+		r.Stop()
+		delete(m.limiters, key)
+	}
+}
+
+// Stop stops every limiter managed by the MultiRateLimiter.
+func (m *MultiRateLimiter) Stop() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for key, r := range m.limiters {
+		r.Stop()
+		delete(m.limiters, key)
+	}
+}
@@ -0,0 +1,28 @@
+package rlimit
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestMultiRateLimiter(t *testing.T) {
+	m := NewMultiRateLimiter(time.Duration(100)*time.Millisecond, 5, time.Second)
+
+	// "host-a" is registered explicitly with its own limit
+	m.Add("host-a", time.Duration(100)*time.Millisecond, 2, time.Second)
+	assert.EqualValues(t, m.LimitLeft("host-a"), 2)
+
+	_, err := m.Wait("host-a")
+	assert.NoError(t, err)
+	assert.EqualValues(t, m.LimitLeft("host-a"), 1)
+
+	// "host-b" is never registered, so it should be lazily created
+	// using the default configuration
+	assert.EqualValues(t, m.LimitLeft("host-b"), 5)
+
+	m.Remove("host-a")
+	assert.EqualValues(t, m.LimitLeft("host-a"), 5)
+
+	m.Stop()
+}
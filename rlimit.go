@@ -8,6 +8,7 @@
 package rlimit
 
 import (
+	"context"
 	"errors"
 	"sync"
 	"time"
@@ -25,6 +26,15 @@ type limits struct {
 
 	// The duration needed to pass before the limit is reset
 	resetInterval time.Duration
+
+	// The time at which limitLeft will next be reset
+	resetAt time.Time
+
+	// A temporary limit that overrides limit while active, used to
+	// ramp down in response to server backpressure (e.g. HTTP 429s)
+	overrideLimit  uint
+	overrideUntil  time.Time
+	overrideActive bool
 }
 
 func (l *limits) SetLimitLeft(i uint) {
@@ -51,6 +61,83 @@ func (l *limits) ResetInterval() time.Duration {
 	return l.resetInterval
 }
 
+func (l *limits) SetResetInterval(d time.Duration) {
+	l.Lock()
+	l.resetInterval = d
+	l.Unlock()
+}
+
+func (l *limits) SetResetAt(t time.Time) {
+	l.Lock()
+	l.resetAt = t
+	l.Unlock()
+}
+
+func (l *limits) ResetAt() time.Time {
+	l.RLock()
+	defer l.RUnlock()
+	return l.resetAt
+}
+
+// SetLimit permanently changes the configured limit.
+func (l *limits) SetLimit(newLimit uint) {
+	l.Lock()
+	l.limit = newLimit
+	l.Unlock()
+}
+
+// SetLimitFor temporarily overrides the configured limit until the given
+// time, after which EffectiveLimit ramps back to the permanent limit.
+func (l *limits) SetLimitFor(newLimit uint, until time.Time) {
+	l.Lock()
+	l.overrideLimit = newLimit
+	l.overrideUntil = until
+	l.overrideActive = true
+	l.Unlock()
+}
+
+// EffectiveLimit returns the limit that should be used the next time
+// limitLeft is refilled: the temporary override if one is active, or the
+// permanent limit otherwise. An expired override is cleared as a side
+// effect so it's only ever consulted once past its expiry.
+func (l *limits) EffectiveLimit() uint {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.overrideActive {
+		if time.Now().Before(l.overrideUntil) {
+			return l.overrideLimit
+		}
+		l.overrideActive = false
+	}
+	return l.limit
+}
+
+// reconfigureParams carries a new tick interval, limit and reset interval
+// into the manager goroutine. A nil *reconfigureParams on a reconfigureCmd
+// means "reset", i.e. reapply the current configuration without changing it.
+type reconfigureParams struct {
+	tickInterval  time.Duration
+	limit         uint
+	resetInterval time.Duration
+}
+
+// reconfigureCmd is sent over a RateLimiter's input channel to the manager
+// goroutine. done is closed once the goroutine has applied the command, so
+// Reset()/Reconfigure()/RespectRetryAfter() can block until their effects
+// are actually visible instead of returning as soon as the channel
+// send/receive rendezvous.
+//
+// delayReset, when non-nil, pushes the reset timer out to fire after that
+// duration instead of the configured resetInterval, and leaves limitLeft
+// untouched (RespectRetryAfter drains it to 0 itself before sending the
+// command) rather than refilling it.
+type reconfigureCmd struct {
+	params     *reconfigureParams
+	delayReset *time.Duration
+	done       chan struct{}
+}
+
 type RateLimiter struct {
 	// Ticker that will be set to tick at the given interval
 	ticker *time.Ticker
@@ -61,10 +148,19 @@ type RateLimiter struct {
 	// Channel to carry the stop event
 	stop chan bool
 
+	// Channel to carry Reset()/Reconfigure() commands into the manager
+	// goroutine, so the ticker and timer are only ever replaced by the
+	// goroutine that owns them, which also stops the current ticker when
+	// it exits
+	input chan *reconfigureCmd
+
 	// Rate limit infomation - protected by a RWMutex
 	limits *limits
 
-	// Channel to carry tick events
+	// Channel to carry tick events. Buffered by one so the manager
+	// goroutine can always hand off a tick without blocking - if the
+	// buffer is already full, the new tick is dropped rather than
+	// stalling the goroutine's own select loop (see NewRateLimiter).
 	Tick chan time.Time
 }
 
@@ -74,14 +170,16 @@ func NewRateLimiter(tickInterval time.Duration, limit uint, resetInterval time.D
 		limit:         limit,
 		limitLeft:     limit,
 		resetInterval: resetInterval,
+		resetAt:       time.Now().Add(resetInterval),
 	}
 
 	r := &RateLimiter{
 		ticker:     time.NewTicker(tickInterval),
 		resetTimer: time.NewTimer(resetInterval),
 		stop:       make(chan bool),
+		input:      make(chan *reconfigureCmd),
 		limits:     l,
-		Tick:       make(chan time.Time),
+		Tick:       make(chan time.Time, 1),
 	}
 
 	// Start a goroutine that manages the state
@@ -91,16 +189,62 @@ func NewRateLimiter(tickInterval time.Duration, limit uint, resetInterval time.D
 			select {
 			case t := <-r.ticker.C:
 				if r.limits.LimitLeft() > 0 {
-					r.Tick <- t
-				} else {
-					// Wait for the reset timer
-					<-r.resetTimer.C
+					select {
+					case r.Tick <- t:
+					default:
+						// Tick's buffer already holds an undelivered
+						// tick - drop this one instead of blocking the
+						// goroutine and starving the other cases below.
+					}
+				}
+				// Otherwise the tick is dropped - the resetTimer case
+				// below is what refills limitLeft.
 
-					// Reset the timer and the limit
-					r.resetTimer.Reset(l.resetInterval)
-					r.limits.SetLimitLeft(r.limits.limit)
+			case <-r.resetTimer.C:
+				// Reset the timer and the limit, consulting any
+				// temporary override set via SetLimitFor/RespectRetryAfter
+				r.resetTimer.Reset(r.limits.ResetInterval())
+				r.limits.SetLimitLeft(r.limits.EffectiveLimit())
+				r.limits.SetResetAt(time.Now().Add(r.limits.ResetInterval()))
+
+			case cmd := <-r.input:
+				if cmd.params != nil {
+					r.ticker.Stop()
+					r.ticker = time.NewTicker(cmd.params.tickInterval)
+					r.limits.SetLimit(cmd.params.limit)
+					r.limits.SetResetInterval(cmd.params.resetInterval)
+				}
+
+				// Stop and recreate the reset timer here, inside the
+				// goroutine that owns it, instead of racing a caller
+				// against the ticker case above.
+				r.resetTimer.Stop()
+				if cmd.delayReset != nil {
+					// Push the reset out by the requested duration and
+					// leave limitLeft as the caller set it (RespectRetryAfter
+					// already drained it to 0) instead of refilling it now.
+					r.resetTimer = time.NewTimer(*cmd.delayReset)
+					r.limits.SetResetAt(time.Now().Add(*cmd.delayReset))
+
+					// Drop a tick still held in Tick's buffer so the
+					// next Wait() blocks until the delayed reset.
+					select {
+					case <-r.Tick:
+					default:
+					}
+				} else {
+					r.resetTimer = time.NewTimer(r.limits.ResetInterval())
+					r.limits.SetLimitLeft(r.limits.EffectiveLimit())
+					r.limits.SetResetAt(time.Now().Add(r.limits.ResetInterval()))
 				}
+
+				// Signal that the command has been fully applied, only
+				// now is it safe for Reset()/Reconfigure()/RespectRetryAfter
+				// to return.
+				close(cmd.done)
+
 			case <-r.stop:
+				r.ticker.Stop()
 				close(r.Tick)
 				return
 			}
@@ -116,6 +260,49 @@ func (r *RateLimiter) Stop() {
 	close(r.stop)
 }
 
+// sendCmd delivers cmd to the manager goroutine and waits for it to be
+// applied, returning an error instead of blocking forever if the limiter
+// is (or becomes) stopped before the goroutine can service it.
+func (r *RateLimiter) sendCmd(cmd *reconfigureCmd) error {
+	select {
+	case r.input <- cmd:
+	case <-r.stop:
+		return errors.New("Rate limiter has stopped")
+	}
+
+	select {
+	case <-cmd.done:
+		return nil
+	case <-r.stop:
+		return errors.New("Rate limiter has stopped")
+	}
+}
+
+// Reset reapplies the limiter's current configuration: limitLeft is
+// refilled and the reset timer is restarted, without dropping any
+// in-flight Wait() callers. Reset blocks until the manager goroutine has
+// applied the change, and returns an error if the limiter is stopped
+// before that happens.
+func (r *RateLimiter) Reset() error {
+	return r.sendCmd(&reconfigureCmd{done: make(chan struct{})})
+}
+
+// Reconfigure safely changes the tick interval, limit, and reset interval
+// of a running limiter. The ticker and reset timer are stopped and
+// recreated inside the manager goroutine rather than racing with it.
+// Reconfigure blocks until the manager goroutine has applied the change,
+// and returns an error if the limiter is stopped before that happens.
+func (r *RateLimiter) Reconfigure(tickInterval time.Duration, limit uint, resetInterval time.Duration) error {
+	return r.sendCmd(&reconfigureCmd{
+		params: &reconfigureParams{
+			tickInterval:  tickInterval,
+			limit:         limit,
+			resetInterval: resetInterval,
+		},
+		done: make(chan struct{}),
+	})
+}
+
 // Blocks until the next tick. Returns the time of the tick, and an error
 // if the rate limiter has been stopped/the tick channel has closed
 func (r *RateLimiter) Wait() (time.Time, error) {
@@ -127,6 +314,47 @@ func (r *RateLimiter) Wait() (time.Time, error) {
 	}
 }
 
+// Blocks until the next tick, or until ctx is cancelled/its deadline elapses,
+// whichever happens first. Returns the time of the tick, and an error if the
+// rate limiter has been stopped or ctx was done before a tick arrived - in
+// the latter case the error is ctx.Err().
+func (r *RateLimiter) WaitContext(ctx context.Context) (time.Time, error) {
+	select {
+	case t, ok := <-r.Tick:
+		if !ok {
+			return time.Time{}, errors.New("Rate limiter has stopped")
+		}
+		r.Count()
+		return t, nil
+	case <-ctx.Done():
+		return time.Time{}, ctx.Err()
+	}
+}
+
+// Reserve reports how long a caller would have to wait before the limit
+// allows another tick, without actually consuming one, so callers can decide
+// whether to skip an operation instead of blocking for it. It returns 0 while
+// ticks are left before the limit, and the time until the next reset once
+// the limit is used up. The wait for the ticker's next tick (up to one tick
+// interval) is not included, so Wait() may still block for that long.
+// Reserve returns ctx.Err() if ctx is already done.
+func (r *RateLimiter) Reserve(ctx context.Context) (time.Duration, error) {
+	select {
+	case <-ctx.Done():
+		return 0, ctx.Err()
+	default:
+	}
+
+	if r.LimitLeft() > 0 {
+		return 0, nil
+	}
+
+	if wait := time.Until(r.limits.ResetAt()); wait > 0 {
+		return wait, nil
+	}
+	return 0, nil
+}
+
 // Decrements the limit left - not to be used when waiting for a tick using Wait()
 func (r *RateLimiter) Count() {
 	r.limits.DecrLimitLeft()
@@ -137,3 +365,31 @@ func (r *RateLimiter) Count() {
 func (r *RateLimiter) LimitLeft() uint {
 	return r.limits.LimitLeft()
 }
+
+// SetLimit permanently changes the number of ticks allowed per reset
+// interval. The new limit takes effect the next time the limit resets.
+func (r *RateLimiter) SetLimit(newLimit uint) {
+	r.limits.SetLimit(newLimit)
+}
+
+// SetLimitFor temporarily overrides the limit until the given time, after
+// which the limiter ramps back to its permanent limit on the next reset.
+// Useful for shrinking the limit in response to server backpressure without
+// tearing down and reconstructing the limiter.
+func (r *RateLimiter) SetLimitFor(newLimit uint, until time.Time) {
+	r.limits.SetLimitFor(newLimit, until)
+}
+
+// RespectRetryAfter immediately drains any remaining ticks and delays the
+// next reset by d, as if reacting to an HTTP 429 response carrying a
+// Retry-After header. Unlike SetLimitFor, this actually pushes the reset
+// timer out by d rather than leaving it to the existing reset cadence, so
+// Reserve()/LimitLeft() reflect the real wait immediately. The limiter
+// ramps back to its permanent limit as soon as the delayed reset fires.
+// RespectRetryAfter blocks until the manager goroutine has applied the
+// change, and returns an error if the limiter is stopped before that
+// happens.
+func (r *RateLimiter) RespectRetryAfter(d time.Duration) error {
+	r.limits.SetLimitLeft(0)
+	return r.sendCmd(&reconfigureCmd{delayReset: &d, done: make(chan struct{})})
+}
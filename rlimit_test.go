@@ -1,6 +1,7 @@
 package rlimit
 
 import (
+	"context"
 	"github.com/stretchr/testify/assert"
 	"testing"
 	"time"
@@ -27,3 +28,120 @@ func TestNewRateLimiter(t *testing.T) {
 	}()
 	r.Stop()
 }
+
+func TestWaitContext(t *testing.T) {
+	r := NewRateLimiter(time.Duration(100)*time.Millisecond, 5, time.Second)
+	defer r.Stop()
+
+	_, err := r.WaitContext(context.Background())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	// The limiter won't tick again within 1ms, so the context should
+	// be done first.
+	_, err = r.WaitContext(ctx)
+	assert.Error(t, err)
+	assert.Equal(t, context.DeadlineExceeded, err)
+}
+
+func TestReserve(t *testing.T) {
+	r := NewRateLimiter(time.Duration(100)*time.Millisecond, 1, time.Second)
+	defer r.Stop()
+
+	// Ticks are left before the limit, so no wait is reported
+	wait, err := r.Reserve(context.Background())
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, wait)
+
+	// Consume the only tick - further reservations should report a
+	// non-zero wait until the limit resets
+	_, err = r.Wait()
+	assert.NoError(t, err)
+
+	wait, err = r.Reserve(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, wait > 0)
+}
+
+func TestSetLimitFor(t *testing.T) {
+	r := NewRateLimiter(time.Duration(10)*time.Millisecond, 5, time.Duration(50)*time.Millisecond)
+	defer r.Stop()
+
+	// Shrink the limit to 1 for a window that covers the first reset (at
+	// 50ms) but not the second (at 100ms)
+	r.SetLimitFor(1, time.Now().Add(time.Duration(60)*time.Millisecond))
+
+	// Wait for a reset to occur so the override is picked up
+	time.Sleep(time.Duration(70) * time.Millisecond)
+	assert.EqualValues(t, 1, r.LimitLeft())
+
+	// The override has expired - the next reset should ramp back to 5
+	time.Sleep(time.Duration(50) * time.Millisecond)
+	assert.EqualValues(t, 5, r.LimitLeft())
+}
+
+func TestRespectRetryAfter(t *testing.T) {
+	r := NewRateLimiter(time.Duration(10)*time.Millisecond, 5, time.Duration(50)*time.Millisecond)
+	defer r.Stop()
+
+	err := r.RespectRetryAfter(time.Duration(200) * time.Millisecond)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, r.LimitLeft())
+
+	// Reserve should reflect the delayed reset, not the limiter's
+	// original (now superseded) reset interval
+	wait, err := r.Reserve(context.Background())
+	assert.NoError(t, err)
+	assert.True(t, wait > time.Duration(100)*time.Millisecond)
+
+	// Once the delay elapses, the limiter should ramp back to its
+	// permanent limit
+	time.Sleep(time.Duration(220) * time.Millisecond)
+	assert.EqualValues(t, 5, r.LimitLeft())
+}
+
+func TestRespectRetryAfterAfterStop(t *testing.T) {
+	r := NewRateLimiter(time.Duration(10)*time.Millisecond, 5, time.Second)
+	r.Stop()
+
+	err := r.RespectRetryAfter(time.Duration(50) * time.Millisecond)
+	assert.Error(t, err)
+}
+
+func TestReset(t *testing.T) {
+	r := NewRateLimiter(time.Duration(10)*time.Millisecond, 5, time.Second)
+	defer r.Stop()
+
+	_, err := r.Wait()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, r.LimitLeft())
+
+	err = r.Reset()
+	assert.NoError(t, err)
+	assert.EqualValues(t, 5, r.LimitLeft())
+}
+
+func TestReconfigure(t *testing.T) {
+	r := NewRateLimiter(time.Duration(10)*time.Millisecond, 5, time.Second)
+	defer r.Stop()
+
+	err := r.Reconfigure(time.Duration(10)*time.Millisecond, 2, time.Second)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, r.LimitLeft())
+
+	_, waitErr := r.Wait()
+	assert.NoError(t, waitErr)
+	_, waitErr = r.Wait()
+	assert.NoError(t, waitErr)
+	assert.EqualValues(t, 0, r.LimitLeft())
+}
+
+func TestReconfigureAfterStop(t *testing.T) {
+	r := NewRateLimiter(time.Duration(10)*time.Millisecond, 5, time.Second)
+	r.Stop()
+
+	err := r.Reconfigure(time.Duration(10)*time.Millisecond, 2, time.Second)
+	assert.Error(t, err)
+}